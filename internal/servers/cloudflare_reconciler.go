@@ -0,0 +1,247 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+// ownershipLabelPrefix marks the TXT record dnsdock writes alongside every
+// record it manages, mirroring external-dns's "heritage" TXT registry. Only
+// records with a matching marker are ever updated or deleted by the
+// reconciler - anything else in the zone is assumed to be user-managed and
+// is left untouched.
+//
+// The prefix goes in front of the record name rather than after it: the
+// marker still has to end in the managed zone's name so it routes through
+// CloudFlareDNSManager.zoneFor the same way the record it marks does.
+const ownershipLabelPrefix = "dnsdock-owner."
+
+// ownershipMarkerName derives the marker name for a (type, name) record.
+// The record type is folded in (lowercased) because a single hostname can
+// carry more than one managed record - e.g. an A and an AAAA published by
+// the same service - and each needs its own marker so deleting one type
+// doesn't strip the marker still protecting the other.
+func ownershipMarkerName(recordType, recordName string) string {
+	return ownershipLabelPrefix + strings.ToLower(recordType) + "." + recordName
+}
+
+func ownershipMarkerContent(serviceName string) string {
+	return fmt.Sprintf("heritage=dnsdock,external-name=%s", serviceName)
+}
+
+// cloudflareRecordKey identifies a record by the (type, name) pair
+// Cloudflare treats as unique for upsert purposes.
+type cloudflareRecordKey struct {
+	recordType string
+	name       string
+}
+
+// DesiredRecord pairs a record with the dnsdock service that published it,
+// so the reconciler can tag the ownership marker it writes alongside the
+// record. A service may appear more than once - one DesiredRecord per
+// record type it publishes (A, AAAA, SRV, ...).
+type DesiredRecord struct {
+	Service string
+	Record  Record
+}
+
+// reconcilePlan is the minimal set of changes needed to converge the cached
+// zone state onto the desired state. Computing it is pure (no API calls),
+// which keeps the diffing logic itself unit-testable.
+type reconcilePlan struct {
+	// upserts holds the records that need creating or updating.
+	upserts []DesiredRecord
+	// deletes holds owned-but-no-longer-desired records to remove.
+	deletes []cloudflare.DNSRecord
+}
+
+// ownedKeys returns the set of record keys in cache that carry a dnsdock
+// ownership TXT marker.
+func ownedKeys(cache map[cloudflareRecordKey]cloudflare.DNSRecord) map[cloudflareRecordKey]bool {
+	owned := map[cloudflareRecordKey]bool{}
+	for key := range cache {
+		if _, ok := cache[cloudflareRecordKey{recordType: "TXT", name: ownershipMarkerName(key.recordType, key.name)}]; ok {
+			owned[key] = true
+		}
+	}
+	return owned
+}
+
+// planReconcile diffs desired against cache and returns the changes needed
+// to converge. previouslyFailed forces a record to be retried even if the
+// cache already matches it - e.g. the record itself upserted fine last time
+// but its ownership marker didn't. A desired record that already exists in
+// the cache without an ownership marker is user-managed and is not upserted.
+func planReconcile(cache map[cloudflareRecordKey]cloudflare.DNSRecord, desired []DesiredRecord, previouslyFailed map[cloudflareRecordKey]error) reconcilePlan {
+	var plan reconcilePlan
+	owned := ownedKeys(cache)
+
+	desiredKeys := map[cloudflareRecordKey]bool{}
+	for _, d := range desired {
+		key := cloudflareRecordKey{recordType: d.Record.Type, name: d.Record.Name}
+		desiredKeys[key] = true
+
+		_, retry := previouslyFailed[key]
+		existing, ok := cache[key]
+		if ok && !owned[key] && !retry {
+			continue // user-managed record: never overwritten
+		}
+		if ok && existing.Content == d.Record.Content && !retry {
+			continue // already up to date and nothing pending from a previous failure
+		}
+		plan.upserts = append(plan.upserts, d)
+	}
+
+	for key := range owned {
+		if desiredKeys[key] {
+			continue
+		}
+		plan.deletes = append(plan.deletes, cache[key])
+	}
+
+	return plan
+}
+
+// CloudFlareReconciler batches DNS changes against the Cloudflare zone(s) of
+// one CloudFlareDNSManager.
+// Instead of issuing a List+Create/Update per container event, it keeps a
+// local cache of the zone's records (refreshed periodically via paginated
+// listing) and reconciles it against the desired state in one pass,
+// producing the minimal set of create/update/delete calls. Changes that
+// fail are remembered so the next Reconcile retries only those, rather than
+// the whole batch.
+type CloudFlareReconciler struct {
+	manager *CloudFlareDNSManager
+
+	mu            sync.Mutex
+	cache         map[cloudflareRecordKey]cloudflare.DNSRecord
+	failedChanges map[cloudflareRecordKey]error
+}
+
+// NewCloudFlareReconciler builds a reconciler around an already-initialized
+// CloudFlareDNSManager.
+func NewCloudFlareReconciler(manager *CloudFlareDNSManager) *CloudFlareReconciler {
+	return &CloudFlareReconciler{
+		manager:       manager,
+		cache:         map[cloudflareRecordKey]cloudflare.DNSRecord{},
+		failedChanges: map[cloudflareRecordKey]error{},
+	}
+}
+
+// RefreshCache lists every record in the managed zone, auto-paginating
+// until Cloudflare reports no more pages, and replaces the local cache with
+// the result.
+func (r *CloudFlareReconciler) RefreshCache(ctx context.Context) error {
+	cache := map[cloudflareRecordKey]cloudflare.DNSRecord{}
+
+	for _, zone := range r.manager.zones {
+		page := 1
+		for {
+			records, resultInfo, err := r.manager.api.ListDNSRecords(ctx, zone.id, cloudflare.ListDNSRecordsParams{
+				ResultInfo: cloudflare.ResultInfo{Page: page, PerPage: 100},
+			})
+			if err != nil {
+				return fmt.Errorf("failed to list DNS records for zone %s (page %d): %w", zone.name, page, err)
+			}
+
+			for _, rec := range records {
+				cache[cloudflareRecordKey{recordType: rec.Type, name: rec.Name}] = rec
+			}
+
+			if len(records) == 0 || page >= resultInfo.TotalPages {
+				break
+			}
+			page++
+		}
+	}
+
+	r.mu.Lock()
+	r.cache = cache
+	r.mu.Unlock()
+
+	logger.Infof("Reconciler cached %d records across %d zone(s)", len(cache), len(r.manager.zones))
+	return nil
+}
+
+// Reconcile diffs the desired set of records (each tagged with the dnsdock
+// service that published it, since a service may publish more than one
+// record type for the same hostname) against the cached zone state and
+// issues the minimal set of create/update/delete calls to converge. Only
+// records carrying dnsdock's ownership TXT marker are ever deleted or
+// overwritten; anything else is left alone.
+//
+// Reconcile works off a local copy of the cache so successful changes are
+// reflected immediately - a record that was just created or deleted isn't
+// re-sent to Cloudflare by a second Reconcile call before the next
+// RefreshCache. Keys that failed on the previous call are always retried,
+// per failedChanges.
+func (r *CloudFlareReconciler) Reconcile(ctx context.Context, desired []DesiredRecord) error {
+	r.mu.Lock()
+	cache := make(map[cloudflareRecordKey]cloudflare.DNSRecord, len(r.cache))
+	for k, v := range r.cache {
+		cache[k] = v
+	}
+	previouslyFailed := r.failedChanges
+	r.mu.Unlock()
+
+	plan := planReconcile(cache, desired, previouslyFailed)
+
+	failed := map[cloudflareRecordKey]error{}
+
+	for _, d := range plan.upserts {
+		rec := d.Record
+		key := cloudflareRecordKey{recordType: rec.Type, name: rec.Name}
+
+		if err := r.manager.UpsertRecord(ctx, rec); err != nil {
+			logger.Warningf("Reconcile: failed to upsert %s record %s: %v", rec.Type, rec.Name, err)
+			failed[key] = err
+			continue
+		}
+		cache[key] = cloudflare.DNSRecord{Type: rec.Type, Name: rec.Name, Content: rec.Content, TTL: rec.TTL}
+
+		markerName := ownershipMarkerName(rec.Type, rec.Name)
+		markerContent := ownershipMarkerContent(d.Service)
+		if err := r.manager.UpsertRecord(ctx, Record{
+			Type:    "TXT",
+			Name:    markerName,
+			Content: markerContent,
+		}); err != nil {
+			logger.Warningf("Reconcile: failed to write ownership marker for %s record %s: %v", rec.Type, rec.Name, err)
+			failed[key] = err
+			continue
+		}
+		cache[cloudflareRecordKey{recordType: "TXT", name: markerName}] = cloudflare.DNSRecord{Type: "TXT", Name: markerName, Content: markerContent}
+	}
+
+	for _, rec := range plan.deletes {
+		key := cloudflareRecordKey{recordType: rec.Type, name: rec.Name}
+
+		if err := r.manager.DeleteRecord(ctx, Record{Type: rec.Type, Name: rec.Name}); err != nil {
+			logger.Warningf("Reconcile: failed to delete stale %s record %s: %v", rec.Type, rec.Name, err)
+			failed[key] = err
+			continue
+		}
+		delete(cache, key)
+
+		markerName := ownershipMarkerName(rec.Type, rec.Name)
+		if err := r.manager.DeleteRecord(ctx, Record{Type: "TXT", Name: markerName}); err != nil {
+			logger.Warningf("Reconcile: failed to delete ownership marker for %s record %s: %v", rec.Type, rec.Name, err)
+			continue
+		}
+		delete(cache, cloudflareRecordKey{recordType: "TXT", name: markerName})
+	}
+
+	r.mu.Lock()
+	r.cache = cache
+	r.failedChanges = failed
+	r.mu.Unlock()
+
+	if len(failed) > 0 {
+		return fmt.Errorf("reconcile: %d record change(s) failed, will retry next pass", len(failed))
+	}
+	return nil
+}
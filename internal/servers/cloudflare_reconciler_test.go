@@ -0,0 +1,124 @@
+package servers
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/cloudflare/cloudflare-go"
+)
+
+func TestOwnershipMarkerNameStaysInZone(t *testing.T) {
+	name := ownershipMarkerName("A", "foo.example.com")
+	if name != "dnsdock-owner.a.foo.example.com" {
+		t.Fatalf("unexpected marker name: %s", name)
+	}
+	if !hasZoneSuffix(name, "example.com") {
+		t.Fatalf("marker name %q does not route to zone example.com", name)
+	}
+}
+
+func TestOwnershipMarkerNameDistinguishesRecordTypes(t *testing.T) {
+	a := ownershipMarkerName("A", "host.example.com")
+	aaaa := ownershipMarkerName("AAAA", "host.example.com")
+	if a == aaaa {
+		t.Fatalf("expected distinct markers for A and AAAA records sharing a name, got %q for both", a)
+	}
+}
+
+// hasZoneSuffix mirrors the label-boundary check CloudFlareDNSManager.zoneFor
+// applies, so this test fails if the marker naming scheme ever stops routing.
+func hasZoneSuffix(name, zone string) bool {
+	return name == zone || len(name) > len(zone) && name[len(name)-len(zone)-1:] == "."+zone
+}
+
+func TestPlanReconcile(t *testing.T) {
+	cache := map[cloudflareRecordKey]cloudflare.DNSRecord{
+		{recordType: "A", name: "stale.example.com"}:                      {Type: "A", Name: "stale.example.com", Content: "1.1.1.1"},
+		{recordType: "TXT", name: "dnsdock-owner.a.stale.example.com"}:    {Type: "TXT", Name: "dnsdock-owner.a.stale.example.com", Content: "heritage=dnsdock,external-name=stale"},
+		{recordType: "A", name: "uptodate.example.com"}:                   {Type: "A", Name: "uptodate.example.com", Content: "2.2.2.2"},
+		{recordType: "TXT", name: "dnsdock-owner.a.uptodate.example.com"}: {Type: "TXT", Name: "dnsdock-owner.a.uptodate.example.com", Content: "heritage=dnsdock,external-name=uptodate"},
+		{recordType: "A", name: "unmanaged.example.com"}:                  {Type: "A", Name: "unmanaged.example.com", Content: "3.3.3.3"},
+	}
+
+	desired := []DesiredRecord{
+		{Service: "uptodate", Record: Record{Type: "A", Name: "uptodate.example.com", Content: "2.2.2.2"}},
+		{Service: "changed", Record: Record{Type: "A", Name: "changed.example.com", Content: "4.4.4.4"}},
+	}
+
+	plan := planReconcile(cache, desired, nil)
+
+	for _, d := range plan.upserts {
+		if d.Service == "uptodate" {
+			t.Fatalf("did not expect an up-to-date record to be re-upserted")
+		}
+	}
+	found := false
+	for _, d := range plan.upserts {
+		if d.Service == "changed" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the missing record to be upserted")
+	}
+	if len(plan.deletes) != 1 || plan.deletes[0].Name != "stale.example.com" {
+		t.Fatalf("expected only the stale owned record to be deleted, got %+v", plan.deletes)
+	}
+}
+
+func TestPlanReconcileSupportsMultipleRecordTypesPerService(t *testing.T) {
+	cache := map[cloudflareRecordKey]cloudflare.DNSRecord{}
+
+	desired := []DesiredRecord{
+		{Service: "dual-stack", Record: Record{Type: "A", Name: "host.example.com", Content: "1.1.1.1"}},
+		{Service: "dual-stack", Record: Record{Type: "AAAA", Name: "host.example.com", Content: "::1"}},
+	}
+
+	plan := planReconcile(cache, desired, nil)
+
+	if len(plan.upserts) != 2 {
+		t.Fatalf("expected both the A and AAAA record to be upserted, got %+v", plan.upserts)
+	}
+}
+
+func TestPlanReconcileDeletingOneTypeDoesNotOrphanTheOther(t *testing.T) {
+	cache := map[cloudflareRecordKey]cloudflare.DNSRecord{
+		{recordType: "A", name: "host.example.com"}:                      {Type: "A", Name: "host.example.com", Content: "1.1.1.1"},
+		{recordType: "TXT", name: "dnsdock-owner.a.host.example.com"}:    {Type: "TXT", Name: "dnsdock-owner.a.host.example.com", Content: "heritage=dnsdock,external-name=dual-stack"},
+		{recordType: "AAAA", name: "host.example.com"}:                   {Type: "AAAA", Name: "host.example.com", Content: "::1"},
+		{recordType: "TXT", name: "dnsdock-owner.aaaa.host.example.com"}: {Type: "TXT", Name: "dnsdock-owner.aaaa.host.example.com", Content: "heritage=dnsdock,external-name=dual-stack"},
+	}
+
+	// Only the AAAA record is still desired - the A record should be
+	// deleted without touching the AAAA record's marker.
+	desired := []DesiredRecord{
+		{Service: "dual-stack", Record: Record{Type: "AAAA", Name: "host.example.com", Content: "::1"}},
+	}
+
+	plan := planReconcile(cache, desired, nil)
+
+	if len(plan.deletes) != 1 || plan.deletes[0].Type != "A" {
+		t.Fatalf("expected only the stale A record to be deleted, got %+v", plan.deletes)
+	}
+	if len(plan.upserts) != 0 {
+		t.Fatalf("did not expect the still-desired AAAA record to be re-upserted, got %+v", plan.upserts)
+	}
+}
+
+func TestPlanReconcileRetriesPreviousFailures(t *testing.T) {
+	cache := map[cloudflareRecordKey]cloudflare.DNSRecord{
+		{recordType: "A", name: "flaky.example.com"}: {Type: "A", Name: "flaky.example.com", Content: "5.5.5.5"},
+	}
+	desired := []DesiredRecord{
+		{Service: "flaky", Record: Record{Type: "A", Name: "flaky.example.com", Content: "5.5.5.5"}},
+	}
+	previouslyFailed := map[cloudflareRecordKey]error{
+		{recordType: "A", name: "flaky.example.com"}: errors.New("stub failure"),
+	}
+
+	plan := planReconcile(cache, desired, previouslyFailed)
+
+	if len(plan.upserts) != 1 || plan.upserts[0].Service != "flaky" {
+		t.Fatalf("expected a previously-failed record to be retried even though content already matches")
+	}
+}
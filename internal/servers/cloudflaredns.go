@@ -3,21 +3,76 @@ package servers
 import (
 	"context"
 	"fmt"
-	"strings"
+	"os"
 
 	"github.com/cloudflare/cloudflare-go"
 )
 
-// CloudFlareDNSManager is a reusable struct that manages DNS operations for a specific Cloudflare zone.
+// cloudflareZone pairs a zone's Cloudflare resource identifier with its
+// name, so a manager can route a record to the right zone by name.
+type cloudflareZone struct {
+	id   *cloudflare.ResourceContainer
+	name string
+}
+
+// CloudFlareDNSManager is a reusable struct that manages DNS operations
+// across one or more Cloudflare zones. When it holds more than one zone
+// (see NewCloudflareDNSManagerFromEnv), a record is routed to whichever
+// zone's name is the longest suffix of the record name.
 type CloudFlareDNSManager struct {
-	api      *cloudflare.API
-	zoneID   *cloudflare.ResourceContainer
-	zoneName string
+	api   *cloudflare.API
+	zones []cloudflareZone
+
+	suffixRewrites  []SuffixRewriteRule
+	strictZoneMatch bool
+}
+
+// zoneFor returns the zone whose name is the longest suffix of name, if any.
+// The match must land on a label boundary - name must equal the zone name or
+// end in "."+zone name - so a zone "example.com" doesn't also claim
+// "notexample.com" or "myexample.com".
+func (d *CloudFlareDNSManager) zoneFor(name string) (cloudflareZone, bool) {
+	best := cloudflareZone{}
+	found := false
+	for _, zone := range d.zones {
+		if !hostnameInZone(name, zone.name) {
+			continue
+		}
+		if !found || len(zone.name) > len(best.name) {
+			best = zone
+			found = true
+		}
+	}
+	return best, found
+}
+
+// CloudFlareOption configures optional behavior of a CloudFlareDNSManager at
+// construction time.
+type CloudFlareOption func(*CloudFlareDNSManager)
+
+// WithSuffixRewriteRules installs rules that rewrite record names (e.g. a
+// legacy short hostname to a different suffix) before they're matched
+// against the managed zone. Rules are evaluated in order; the first one
+// that matches wins.
+func WithSuffixRewriteRules(rules ...SuffixRewriteRule) CloudFlareOption {
+	return func(d *CloudFlareDNSManager) {
+		d.suffixRewrites = rules
+	}
+}
+
+// WithStrictZoneMatch makes UpsertRecord/DeleteRecord return an error
+// instead of just logging a warning when a record name doesn't fall under
+// the managed zone, so misconfigured hostnames are caught early rather than
+// silently dropped.
+func WithStrictZoneMatch(strict bool) CloudFlareOption {
+	return func(d *CloudFlareDNSManager) {
+		d.strictZoneMatch = strict
+	}
 }
 
 // NewCloudflareDNSManager initializes a DNSManager for the specified zone using the provided API token.
 // It fetches the zone ID once and reuses it for subsequent operations.
-func NewCloudflareDNSManager(apiToken, zoneName string) (*CloudFlareDNSManager, error) {
+func NewCloudflareDNSManager(apiToken, zoneName string, opts ...CloudFlareOption) (*CloudFlareDNSManager, error) {
 	// Initialize the Cloudflare API client.
 	api, err := cloudflare.NewWithAPIToken(apiToken)
 	if err != nil {
@@ -30,11 +85,85 @@ func NewCloudflareDNSManager(apiToken, zoneName string) (*CloudFlareDNSManager,
 		return nil, fmt.Errorf("failed to get zone ID for %s: %w", zoneName, err)
 	}
 
-	return &CloudFlareDNSManager{
-		api:      api,
-		zoneID:   cloudflare.ZoneIdentifier(zoneID),
-		zoneName: zoneName,
-	}, nil
+	d := &CloudFlareDNSManager{
+		api:   api,
+		zones: []cloudflareZone{{id: cloudflare.ZoneIdentifier(zoneID), name: zoneName}},
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d, nil
+}
+
+// NewCloudflareDNSManagerFromEnv builds a CloudFlareDNSManager from
+// environment variables, following the credential-loading pattern used by
+// lego/go-acme and go-ethereum's devp2p:
+//
+//   - CLOUDFLARE_API_TOKEN, if set, is used directly (the preferred path).
+//   - Otherwise CLOUDFLARE_EMAIL and CLOUDFLARE_API_KEY are used together as
+//     the legacy email+global-API-key credential pair.
+//   - CLOUDFLARE_ZONE_ID, if set, is used as-is and skips the ZoneIDByName
+//     lookup.
+//   - CLOUDFLARE_ZONE_NAME, if set (and CLOUDFLARE_ZONE_ID is not), is
+//     resolved to a zone ID the normal way.
+//   - If neither is set, every zone the credential can see is discovered via
+//     ListZones and the manager routes each record to whichever zone's name
+//     is the longest suffix of the record name.
+func NewCloudflareDNSManagerFromEnv(opts ...CloudFlareOption) (*CloudFlareDNSManager, error) {
+	var api *cloudflare.API
+	var err error
+
+	if token := os.Getenv("CLOUDFLARE_API_TOKEN"); token != "" {
+		api, err = cloudflare.NewWithAPIToken(token)
+	} else if email, key := os.Getenv("CLOUDFLARE_EMAIL"), os.Getenv("CLOUDFLARE_API_KEY"); email != "" && key != "" {
+		api, err = cloudflare.New(key, email)
+	} else {
+		return nil, fmt.Errorf("no Cloudflare credentials found: set CLOUDFLARE_API_TOKEN, or CLOUDFLARE_EMAIL and CLOUDFLARE_API_KEY")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Cloudflare API client: %w", err)
+	}
+
+	ctx := context.Background()
+	d := &CloudFlareDNSManager{api: api}
+
+	switch {
+	case os.Getenv("CLOUDFLARE_ZONE_ID") != "":
+		zoneID := os.Getenv("CLOUDFLARE_ZONE_ID")
+		zone, err := api.ZoneDetails(ctx, zoneID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up zone %s: %w", zoneID, err)
+		}
+		d.zones = []cloudflareZone{{id: cloudflare.ZoneIdentifier(zoneID), name: zone.Name}}
+
+	case os.Getenv("CLOUDFLARE_ZONE_NAME") != "":
+		zoneName := os.Getenv("CLOUDFLARE_ZONE_NAME")
+		zoneID, err := api.ZoneIDByName(zoneName)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get zone ID for %s: %w", zoneName, err)
+		}
+		d.zones = []cloudflareZone{{id: cloudflare.ZoneIdentifier(zoneID), name: zoneName}}
+
+	default:
+		zones, err := api.ListZones(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to discover zones: %w", err)
+		}
+		if len(zones) == 0 {
+			return nil, fmt.Errorf("no zones are visible to this Cloudflare credential")
+		}
+		for _, zone := range zones {
+			d.zones = append(d.zones, cloudflareZone{id: cloudflare.ZoneIdentifier(zone.ID), name: zone.Name})
+		}
+		logger.Infof("Discovered %d Cloudflare zone(s) for automatic routing", len(d.zones))
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d, nil
 }
 
 // UpdateARecord updates or creates an "A" DNS record in the managed zone.
@@ -42,25 +171,43 @@ func NewCloudflareDNSManager(apiToken, zoneName string) (*CloudFlareDNSManager,
 // - recordName: The name of the DNS record to update (e.g., "sub.example.com").
 // - ipAddress: The IP address to set for the "A" record.
 // - ttl: The Time-To-Live (TTL) for the DNS record.
+//
+// Deprecated: use UpsertRecord, which supports record types beyond "A".
 func (d *CloudFlareDNSManager) UpdateARecord(recordName, ipAddress string, ttl int) error {
+	return d.UpsertRecord(context.Background(), Record{
+		Type:    "A",
+		Name:    recordName,
+		Content: ipAddress,
+		TTL:     ttl,
+	})
+}
 
-	if strings.HasSuffix(recordName, "ls90") {
-		recordName = recordName + ".co"
+// UpsertRecord creates or updates rec in the managed zone, implementing
+// DNSProvider.
+func (d *CloudFlareDNSManager) UpsertRecord(ctx context.Context, rec Record) error {
+	if !SupportedRecordTypes[rec.Type] {
+		return fmt.Errorf("cloudflare: unsupported record type %q", rec.Type)
 	}
 
-	if !strings.HasSuffix(recordName, d.zoneName) {
-		logger.Warningf("Ignoring record %s: does not being to the zone", recordName)
+	recordName := d.rewriteName(rec.Name)
+
+	zone, ok := d.zoneFor(recordName)
+	if !ok {
+		if d.strictZoneMatch {
+			return fmt.Errorf("record %s does not belong to any managed zone", recordName)
+		}
+		logger.Warningf("Ignoring record %s: does not belong to the zone", recordName)
 		return nil
 	}
 
+	ttl := rec.TTL
 	if ttl < 1 {
 		ttl = 60 // Default TTL of 60 seconds.
 	}
 
 	// Check if the DNS record already exists.
-	ctx := context.Background()
-	records, _, err := d.api.ListDNSRecords(ctx, d.zoneID, cloudflare.ListDNSRecordsParams{
-		Type: "A",
+	records, _, err := d.api.ListDNSRecords(ctx, zone.id, cloudflare.ListDNSRecordsParams{
+		Type: rec.Type,
 		Name: recordName,
 	})
 	if err != nil {
@@ -69,36 +216,111 @@ func (d *CloudFlareDNSManager) UpdateARecord(recordName, ipAddress string, ttl i
 
 	if len(records) > 0 {
 		// Update the existing record if found.
-		if records[0].Content == ipAddress {
-			logger.Debugf("A record %s already up-to-date", recordName)
+		if records[0].Content == rec.Content {
+			logger.Debugf("%s record %s already up-to-date", rec.Type, recordName)
 			return nil
 		}
-		_, err = d.api.UpdateDNSRecord(ctx, d.zoneID, cloudflare.UpdateDNSRecordParams{
-			ID:      records[0].ID,
-			Type:    "A",
-			Name:    recordName,
-			Content: ipAddress,
-			TTL:     ttl,
+		_, err = d.api.UpdateDNSRecord(ctx, zone.id, cloudflare.UpdateDNSRecordParams{
+			ID:       records[0].ID,
+			Type:     rec.Type,
+			Name:     recordName,
+			Content:  rec.Content,
+			TTL:      ttl,
+			Priority: rec.Options.Priority,
+			Proxied:  rec.Options.Proxied,
 		})
 		if err != nil {
 			return fmt.Errorf("failed to update DNS record: %w", err)
 		}
-		logger.Infof("Updated A record: %s -> %s", recordName, ipAddress)
+		logger.Infof("Updated %s record: %s -> %s", rec.Type, recordName, rec.Content)
 	} else {
 		// Create a new record if no existing record is found.
 		newRecord := cloudflare.CreateDNSRecordParams{
-			Type:    "A",
-			Name:    recordName,
-			Content: ipAddress,
-			TTL:     ttl,
+			Type:     rec.Type,
+			Name:     recordName,
+			Content:  rec.Content,
+			TTL:      ttl,
+			Priority: rec.Options.Priority,
+			Proxied:  rec.Options.Proxied,
 		}
 
-		_, err = d.api.CreateDNSRecord(ctx, d.zoneID, newRecord)
+		_, err = d.api.CreateDNSRecord(ctx, zone.id, newRecord)
 		if err != nil {
 			return fmt.Errorf("failed to create DNS record: %w", err)
 		}
-		logger.Infof("Created new A record: %s -> %s", recordName, ipAddress)
+		logger.Infof("Created new %s record: %s -> %s", rec.Type, recordName, rec.Content)
+	}
+
+	return nil
+}
+
+// DeleteRecord removes the record matching rec's type and name from the
+// managed zone, implementing DNSProvider.
+func (d *CloudFlareDNSManager) DeleteRecord(ctx context.Context, rec Record) error {
+	recordName := d.rewriteName(rec.Name)
+
+	zone, ok := d.zoneFor(recordName)
+	if !ok {
+		if d.strictZoneMatch {
+			return fmt.Errorf("record %s does not belong to any managed zone", recordName)
+		}
+		logger.Warningf("Ignoring record %s: does not belong to the zone", recordName)
+		return nil
+	}
+
+	records, _, err := d.api.ListDNSRecords(ctx, zone.id, cloudflare.ListDNSRecordsParams{
+		Type: rec.Type,
+		Name: recordName,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch DNS records: %w", err)
+	}
+
+	for _, existing := range records {
+		if err := d.api.DeleteDNSRecord(ctx, zone.id, existing.ID); err != nil {
+			return fmt.Errorf("failed to delete DNS record %s: %w", recordName, err)
+		}
+		logger.Infof("Deleted %s record: %s", rec.Type, recordName)
 	}
 
 	return nil
 }
+
+// rewriteName applies the manager's suffix rewrite rules to name, in order,
+// stopping at the first rule that matches.
+func (d *CloudFlareDNSManager) rewriteName(name string) string {
+	for _, rule := range d.suffixRewrites {
+		if rewritten, ok := rule.Apply(name); ok {
+			return rewritten
+		}
+	}
+	return name
+}
+
+// ListRecords returns every record Cloudflare has on file across all of the
+// manager's zones, implementing DNSProvider.
+func (d *CloudFlareDNSManager) ListRecords(ctx context.Context) ([]Record, error) {
+	var result []Record
+	for _, zone := range d.zones {
+		records, _, err := d.api.ListDNSRecords(ctx, zone.id, cloudflare.ListDNSRecordsParams{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch DNS records for zone %s: %w", zone.name, err)
+		}
+
+		for _, r := range records {
+			result = append(result, Record{
+				Type:    r.Type,
+				Name:    r.Name,
+				Content: r.Content,
+				TTL:     r.TTL,
+				Options: RecordOptions{
+					Proxied: r.Proxied,
+				},
+			})
+		}
+	}
+	return result, nil
+}
+
+// var _ documents that CloudFlareDNSManager satisfies DNSProvider.
+var _ DNSProvider = (*CloudFlareDNSManager)(nil)
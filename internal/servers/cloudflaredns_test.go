@@ -0,0 +1,34 @@
+package servers
+
+import "testing"
+
+func TestZoneForRespectsLabelBoundary(t *testing.T) {
+	d := &CloudFlareDNSManager{zones: []cloudflareZone{
+		{name: "example.com"},
+		{name: "sub.example.com"},
+	}}
+
+	tests := []struct {
+		name      string
+		wantZone  string
+		wantFound bool
+	}{
+		{"example.com", "example.com", true},
+		{"foo.example.com", "example.com", true},
+		{"foo.sub.example.com", "sub.example.com", true}, // longest matching suffix wins
+		{"notexample.com", "", false},                    // must not match on a bare suffix
+		{"myexample.com", "", false},
+		{"other.com", "", false},
+	}
+
+	for _, tt := range tests {
+		zone, ok := d.zoneFor(tt.name)
+		if ok != tt.wantFound {
+			t.Errorf("zoneFor(%q) found = %v, want %v", tt.name, ok, tt.wantFound)
+			continue
+		}
+		if ok && zone.name != tt.wantZone {
+			t.Errorf("zoneFor(%q) = %q, want %q", tt.name, zone.name, tt.wantZone)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package servers
+
+import "context"
+
+// Record is a provider-agnostic representation of a single DNS resource
+// record that dnsdock wants published. Options carries provider-specific
+// knobs that don't apply universally (e.g. Cloudflare's proxy toggle) -
+// providers that don't understand a field simply ignore it.
+type Record struct {
+	Type    string
+	Name    string
+	Content string
+	TTL     int
+	Options RecordOptions
+}
+
+// RecordOptions carries fields that only some DNSProviders understand.
+// A nil pointer means "let the provider pick its own default" rather than
+// "explicitly off/zero".
+type RecordOptions struct {
+	// Proxied toggles Cloudflare's orange-cloud proxying for A/AAAA/CNAME
+	// records. Ignored by every other provider.
+	Proxied *bool
+
+	// Priority is the priority field of an SRV record.
+	Priority *uint16
+}
+
+// SupportedRecordTypes are the record types dnsdock knows how to derive from
+// container labels and publish through a DNSProvider.
+var SupportedRecordTypes = map[string]bool{
+	"A":     true,
+	"AAAA":  true,
+	"CNAME": true,
+	"TXT":   true,
+	"SRV":   true,
+}
+
+// DNSProvider is implemented by anything capable of managing DNS records on
+// behalf of dnsdock. This mirrors the adapter pattern used by libdns and
+// external-dns: a dnsdock server can hold several DNSProviders at once and
+// fan out record updates to whichever one owns the zone for a given
+// container hostname.
+type DNSProvider interface {
+	// UpsertRecord creates rec if no record of the same type and name exists
+	// yet, or updates it in place if one does.
+	UpsertRecord(ctx context.Context, rec Record) error
+
+	// DeleteRecord removes the record matching rec's type and name, if one
+	// exists. Deleting a record that doesn't exist is not an error.
+	DeleteRecord(ctx context.Context, rec Record) error
+
+	// ListRecords returns every record the provider currently knows about.
+	ListRecords(ctx context.Context) ([]Record, error)
+}
@@ -0,0 +1,73 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// HurricaneElectricDNSManager updates records hosted on Hurricane Electric's
+// free DNS service via its dyndns2-compatible update endpoint
+// (https://dns.he.net/docs.html). HE only exposes a single "update the
+// current value of this hostname" call, so it has no concept of listing or
+// deleting records - those operations are not supported.
+type HurricaneElectricDNSManager struct {
+	client   *http.Client
+	username string
+	password string
+}
+
+// NewHurricaneElectricDNSManager builds a DNSProvider backed by Hurricane
+// Electric's dynamic DNS update API. username/password are the hostname's
+// dynamic DNS credentials as configured in the HE control panel.
+func NewHurricaneElectricDNSManager(username, password string) *HurricaneElectricDNSManager {
+	return &HurricaneElectricDNSManager{
+		client:   http.DefaultClient,
+		username: username,
+		password: password,
+	}
+}
+
+// UpsertRecord pushes rec's content as the new value for the hostname in
+// rec.Name via HE's dyndns2 update call. HE only supports A/AAAA records.
+func (d *HurricaneElectricDNSManager) UpsertRecord(ctx context.Context, rec Record) error {
+	if rec.Type != "A" && rec.Type != "AAAA" {
+		return fmt.Errorf("hurricane electric: unsupported record type %q", rec.Type)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://dyn.dns.he.net/nic/update", nil)
+	if err != nil {
+		return fmt.Errorf("failed to build dyndns2 request: %w", err)
+	}
+	req.SetBasicAuth(d.username, d.password)
+	q := url.Values{}
+	q.Set("hostname", rec.Name)
+	q.Set("myip", rec.Content)
+	req.URL.RawQuery = q.Encode()
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("dyndns2 update failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("dyndns2 update returned status %s", resp.Status)
+	}
+
+	logger.Infof("Updated %s record via Hurricane Electric: %s -> %s", rec.Type, rec.Name, rec.Content)
+	return nil
+}
+
+// DeleteRecord is not supported by HE's dyndns2 API.
+func (d *HurricaneElectricDNSManager) DeleteRecord(ctx context.Context, rec Record) error {
+	return fmt.Errorf("hurricane electric: deleting records is not supported")
+}
+
+// ListRecords is not supported by HE's dyndns2 API.
+func (d *HurricaneElectricDNSManager) ListRecords(ctx context.Context) ([]Record, error) {
+	return nil, fmt.Errorf("hurricane electric: listing records is not supported")
+}
+
+var _ DNSProvider = (*HurricaneElectricDNSManager)(nil)
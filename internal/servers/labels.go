@@ -0,0 +1,117 @@
+package servers
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Container labels that control which DNS records get published for a
+// service. Only com.dnsdock.aaaa/cname/txt/srv add a record; a plain A
+// record is still derived from the container's own address as before.
+const (
+	LabelAAAA    = "com.dnsdock.aaaa"
+	LabelCNAME   = "com.dnsdock.cname"
+	LabelTXT     = "com.dnsdock.txt"
+	LabelSRV     = "com.dnsdock.srv"
+	LabelProxied = "com.dnsdock.proxied"
+)
+
+// RecordsFromLabels derives the extra (non-A) records a container wants
+// published, given its labels and the hostname the A record is published
+// under. Unset labels produce no record for that type. A malformed
+// com.dnsdock.srv label is logged and skipped rather than failing the whole
+// service.
+func RecordsFromLabels(hostname string, labels map[string]string) []Record {
+	proxied, hasProxied := parseBoolLabel(labels[LabelProxied])
+
+	var records []Record
+	if content, ok := labels[LabelAAAA]; ok && content != "" {
+		records = append(records, Record{Type: "AAAA", Name: hostname, Content: content, Options: proxiedOptions(hasProxied, proxied)})
+	}
+	if content, ok := labels[LabelCNAME]; ok && content != "" {
+		records = append(records, Record{Type: "CNAME", Name: hostname, Content: content, Options: proxiedOptions(hasProxied, proxied)})
+	}
+	if content, ok := labels[LabelTXT]; ok && content != "" {
+		records = append(records, Record{Type: "TXT", Name: hostname, Content: content})
+	}
+	if value, ok := labels[LabelSRV]; ok && value != "" {
+		srv, err := ParseSRVLabel(value)
+		if err != nil {
+			logger.Warningf("Ignoring invalid %s label %q: %v", LabelSRV, value, err)
+		} else {
+			records = append(records, srv.Record(hostname))
+		}
+	}
+	return records
+}
+
+// SRVLabel is the parsed form of a com.dnsdock.srv label.
+type SRVLabel struct {
+	Priority uint16
+	Weight   uint16
+	Port     uint16
+	Target   string
+}
+
+// ParseSRVLabel parses a com.dnsdock.srv label value of the form
+// "priority weight port target", e.g. "10 20 5060 sip.example.com".
+func ParseSRVLabel(value string) (SRVLabel, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 4 {
+		return SRVLabel{}, fmt.Errorf("expected \"priority weight port target\", got %q", value)
+	}
+
+	priority, err := parseUint16(fields[0])
+	if err != nil {
+		return SRVLabel{}, fmt.Errorf("invalid priority: %w", err)
+	}
+	weight, err := parseUint16(fields[1])
+	if err != nil {
+		return SRVLabel{}, fmt.Errorf("invalid weight: %w", err)
+	}
+	port, err := parseUint16(fields[2])
+	if err != nil {
+		return SRVLabel{}, fmt.Errorf("invalid port: %w", err)
+	}
+
+	return SRVLabel{Priority: priority, Weight: weight, Port: port, Target: fields[3]}, nil
+}
+
+// Record builds the SRV Record this label describes for the given service
+// name. Content is the full zone-file form Cloudflare expects and returns
+// from ListDNSRecords - "priority weight port target" - so the up-to-date
+// comparison in CloudFlareDNSManager.UpsertRecord actually matches.
+func (s SRVLabel) Record(name string) Record {
+	return Record{
+		Type:    "SRV",
+		Name:    name,
+		Content: fmt.Sprintf("%d %d %d %s", s.Priority, s.Weight, s.Port, s.Target),
+	}
+}
+
+func parseUint16(s string) (uint16, error) {
+	v, err := strconv.ParseUint(s, 10, 16)
+	if err != nil {
+		return 0, err
+	}
+	return uint16(v), nil
+}
+
+func proxiedOptions(has bool, value bool) RecordOptions {
+	if !has {
+		return RecordOptions{}
+	}
+	return RecordOptions{Proxied: &value}
+}
+
+func parseBoolLabel(value string) (val bool, ok bool) {
+	if value == "" {
+		return false, false
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, false
+	}
+	return parsed, true
+}
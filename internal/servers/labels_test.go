@@ -0,0 +1,64 @@
+package servers
+
+import "testing"
+
+func TestParseSRVLabel(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    SRVLabel
+		wantErr bool
+	}{
+		{
+			name:  "valid",
+			value: "10 20 5060 sip.example.com",
+			want:  SRVLabel{Priority: 10, Weight: 20, Port: 5060, Target: "sip.example.com"},
+		},
+		{name: "too few fields", value: "10 20 5060", wantErr: true},
+		{name: "too many fields", value: "10 20 5060 sip.example.com extra", wantErr: true},
+		{name: "non-numeric priority", value: "x 20 5060 sip.example.com", wantErr: true},
+		{name: "port out of range", value: "10 20 99999 sip.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSRVLabel(tt.value)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSRVLabel(%q) expected an error, got %+v", tt.value, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSRVLabel(%q) unexpected error: %v", tt.value, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseSRVLabel(%q) = %+v, want %+v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordsFromLabelsSRV(t *testing.T) {
+	records := RecordsFromLabels("sip.example.com", map[string]string{
+		LabelSRV: "10 20 5060 target.example.com",
+	})
+
+	if len(records) != 1 {
+		t.Fatalf("expected exactly one record, got %d: %+v", len(records), records)
+	}
+
+	rec := records[0]
+	if rec.Type != "SRV" || rec.Name != "sip.example.com" || rec.Content != "10 20 5060 target.example.com" {
+		t.Fatalf("unexpected SRV record: %+v", rec)
+	}
+}
+
+func TestRecordsFromLabelsInvalidSRVIsSkipped(t *testing.T) {
+	records := RecordsFromLabels("sip.example.com", map[string]string{
+		LabelSRV: "not-a-valid-srv-value",
+	})
+	if len(records) != 0 {
+		t.Fatalf("expected an invalid SRV label to be skipped, got %+v", records)
+	}
+}
@@ -0,0 +1,33 @@
+package servers
+
+import "context"
+
+// NoopDNSProvider is a DNSProvider that logs the record operations it would
+// have performed instead of talking to a real backend. It's useful for
+// local development and for testing the code that fans out to providers
+// without needing live credentials.
+type NoopDNSProvider struct{}
+
+// NewNoopDNSProvider returns a DNSProvider that only logs.
+func NewNoopDNSProvider() *NoopDNSProvider {
+	return &NoopDNSProvider{}
+}
+
+// UpsertRecord logs the record that would have been created or updated.
+func (d *NoopDNSProvider) UpsertRecord(ctx context.Context, rec Record) error {
+	logger.Infof("[noop] upsert %s record: %s -> %s (ttl=%d)", rec.Type, rec.Name, rec.Content, rec.TTL)
+	return nil
+}
+
+// DeleteRecord logs the record that would have been deleted.
+func (d *NoopDNSProvider) DeleteRecord(ctx context.Context, rec Record) error {
+	logger.Infof("[noop] delete %s record: %s", rec.Type, rec.Name)
+	return nil
+}
+
+// ListRecords always returns an empty list: the no-op provider keeps no state.
+func (d *NoopDNSProvider) ListRecords(ctx context.Context) ([]Record, error) {
+	return nil, nil
+}
+
+var _ DNSProvider = (*NoopDNSProvider)(nil)
@@ -0,0 +1,90 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+)
+
+// providerRoute associates a DNSProvider with the zones it's responsible
+// for.
+type providerRoute struct {
+	provider DNSProvider
+	zones    []string
+}
+
+// DNSProviderSet is the dnsdock server's entry point for DNS updates: it
+// holds every configured DNSProvider (Cloudflare, Hurricane Electric,
+// RFC2136, ...) and fans a record out to whichever one owns the zone for
+// its hostname, so a single dnsdock instance can publish the same
+// container against several external DNS backends at once. It is itself a
+// DNSProvider, so it composes the same way a single provider would.
+type DNSProviderSet struct {
+	routes []providerRoute
+}
+
+// NewDNSProviderSet builds an empty set; use Register to add providers.
+func NewDNSProviderSet() *DNSProviderSet {
+	return &DNSProviderSet{}
+}
+
+// Register makes provider responsible for any record whose name falls
+// under one of zones.
+func (s *DNSProviderSet) Register(provider DNSProvider, zones ...string) {
+	s.routes = append(s.routes, providerRoute{provider: provider, zones: zones})
+}
+
+// providerFor returns the provider responsible for name: whichever
+// registered zone is the longest match for it.
+func (s *DNSProviderSet) providerFor(name string) (DNSProvider, bool) {
+	var best DNSProvider
+	bestZoneLen := -1
+	found := false
+
+	for _, route := range s.routes {
+		for _, zone := range route.zones {
+			if !hostnameInZone(name, zone) {
+				continue
+			}
+			if len(zone) > bestZoneLen {
+				bestZoneLen = len(zone)
+				best = route.provider
+				found = true
+			}
+		}
+	}
+
+	return best, found
+}
+
+// UpsertRecord dispatches rec to the provider owning its zone.
+func (s *DNSProviderSet) UpsertRecord(ctx context.Context, rec Record) error {
+	provider, ok := s.providerFor(rec.Name)
+	if !ok {
+		return fmt.Errorf("no DNS provider registered for %s", rec.Name)
+	}
+	return provider.UpsertRecord(ctx, rec)
+}
+
+// DeleteRecord dispatches rec to the provider owning its zone.
+func (s *DNSProviderSet) DeleteRecord(ctx context.Context, rec Record) error {
+	provider, ok := s.providerFor(rec.Name)
+	if !ok {
+		return fmt.Errorf("no DNS provider registered for %s", rec.Name)
+	}
+	return provider.DeleteRecord(ctx, rec)
+}
+
+// ListRecords returns the combined records of every registered provider.
+func (s *DNSProviderSet) ListRecords(ctx context.Context) ([]Record, error) {
+	var all []Record
+	for _, route := range s.routes {
+		records, err := route.provider.ListRecords(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list records from provider: %w", err)
+		}
+		all = append(all, records...)
+	}
+	return all, nil
+}
+
+var _ DNSProvider = (*DNSProviderSet)(nil)
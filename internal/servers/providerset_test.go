@@ -0,0 +1,79 @@
+package servers
+
+import (
+	"context"
+	"testing"
+)
+
+// recordingProvider is a DNSProvider test double that records the records
+// it was asked to upsert/delete, so tests can assert which provider a
+// DNSProviderSet routed a record to.
+type recordingProvider struct {
+	upserted []Record
+	deleted  []Record
+}
+
+func (p *recordingProvider) UpsertRecord(ctx context.Context, rec Record) error {
+	p.upserted = append(p.upserted, rec)
+	return nil
+}
+
+func (p *recordingProvider) DeleteRecord(ctx context.Context, rec Record) error {
+	p.deleted = append(p.deleted, rec)
+	return nil
+}
+
+func (p *recordingProvider) ListRecords(ctx context.Context) ([]Record, error) {
+	return p.upserted, nil
+}
+
+var _ DNSProvider = (*recordingProvider)(nil)
+
+func TestDNSProviderSetRoutesByZoneOwnership(t *testing.T) {
+	cloudflare := &recordingProvider{}
+	hurricane := &recordingProvider{}
+
+	set := NewDNSProviderSet()
+	set.Register(cloudflare, "example.com")
+	set.Register(hurricane, "example.net")
+
+	if err := set.UpsertRecord(context.Background(), Record{Type: "A", Name: "app.example.com", Content: "1.1.1.1"}); err != nil {
+		t.Fatalf("UpsertRecord: %v", err)
+	}
+	if err := set.UpsertRecord(context.Background(), Record{Type: "A", Name: "app.example.net", Content: "2.2.2.2"}); err != nil {
+		t.Fatalf("UpsertRecord: %v", err)
+	}
+
+	if len(cloudflare.upserted) != 1 || cloudflare.upserted[0].Name != "app.example.com" {
+		t.Fatalf("expected the .com record to route to the cloudflare provider, got %+v", cloudflare.upserted)
+	}
+	if len(hurricane.upserted) != 1 || hurricane.upserted[0].Name != "app.example.net" {
+		t.Fatalf("expected the .net record to route to the hurricane provider, got %+v", hurricane.upserted)
+	}
+}
+
+func TestDNSProviderSetErrorsWithNoOwner(t *testing.T) {
+	set := NewDNSProviderSet()
+	set.Register(&recordingProvider{}, "example.com")
+
+	err := set.UpsertRecord(context.Background(), Record{Type: "A", Name: "app.unrelated.org", Content: "1.1.1.1"})
+	if err == nil {
+		t.Fatal("expected an error when no provider owns the record's zone")
+	}
+}
+
+// TestDNSProviderSetAcceptsEveryAdapter is a compile-time-ish smoke test
+// that every DNSProvider implementation can actually be registered into a
+// set together, since Register takes the interface rather than a concrete
+// type.
+func TestDNSProviderSetAcceptsEveryAdapter(t *testing.T) {
+	set := NewDNSProviderSet()
+	set.Register(NewNoopDNSProvider(), "noop.example.com")
+	set.Register(NewHurricaneElectricDNSManager("user", "pass"), "he.example.com")
+	set.Register(NewRFC2136DNSManager("ns1.example.com:53", "rfc2136.example.com", "key", "secret", ""), "rfc2136.example.com")
+	set.Register(&CloudFlareDNSManager{}, "cloudflare.example.com")
+
+	if len(set.routes) != 4 {
+		t.Fatalf("expected 4 registered providers, got %d", len(set.routes))
+	}
+}
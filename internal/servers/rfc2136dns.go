@@ -0,0 +1,101 @@
+package servers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// RFC2136DNSManager manages records on any nameserver that accepts RFC 2136
+// dynamic DNS updates (e.g. BIND, PowerDNS, Knot), authenticated with a TSIG
+// key.
+type RFC2136DNSManager struct {
+	nameserver string // host:port of the authoritative server, e.g. "ns1.example.com:53"
+	zoneName   string
+	tsigKey    string
+	tsigSecret string
+	tsigAlgo   string // e.g. dns.HmacSHA256
+}
+
+// NewRFC2136DNSManager builds a DNSProvider that issues RFC 2136 dynamic
+// updates against nameserver for the given zone, authenticated with the
+// supplied TSIG key/secret pair.
+func NewRFC2136DNSManager(nameserver, zoneName, tsigKey, tsigSecret, tsigAlgo string) *RFC2136DNSManager {
+	if tsigAlgo == "" {
+		tsigAlgo = dns.HmacSHA256
+	}
+	return &RFC2136DNSManager{
+		nameserver: nameserver,
+		zoneName:   dns.Fqdn(zoneName),
+		tsigKey:    dns.Fqdn(tsigKey),
+		tsigSecret: tsigSecret,
+		tsigAlgo:   tsigAlgo,
+	}
+}
+
+func (d *RFC2136DNSManager) exchange(m *dns.Msg) error {
+	m.SetTsig(d.tsigKey, d.tsigAlgo, 300, time.Now().Unix())
+
+	c := new(dns.Client)
+	c.TsigSecret = map[string]string{d.tsigKey: d.tsigSecret}
+
+	reply, _, err := c.Exchange(m, d.nameserver)
+	if err != nil {
+		return fmt.Errorf("rfc2136 exchange failed: %w", err)
+	}
+	if reply.Rcode != dns.RcodeSuccess {
+		return fmt.Errorf("rfc2136 update rejected: %s", dns.RcodeToString[reply.Rcode])
+	}
+	return nil
+}
+
+// UpsertRecord replaces any existing records of rec's type and name with
+// rec, via an RFC 2136 "delete then add" update.
+func (d *RFC2136DNSManager) UpsertRecord(ctx context.Context, rec Record) error {
+	rr, err := dns.NewRR(fmt.Sprintf("%s %d IN %s %s", dns.Fqdn(rec.Name), ttlOrDefault(rec.TTL), rec.Type, rec.Content))
+	if err != nil {
+		return fmt.Errorf("failed to build resource record: %w", err)
+	}
+
+	m := new(dns.Msg)
+	m.SetUpdate(d.zoneName)
+	m.RemoveRRset([]dns.RR{&dns.ANY{Hdr: dns.RR_Header{Name: dns.Fqdn(rec.Name), Rrtype: dns.StringToType[rec.Type], Class: dns.ClassANY}}})
+	m.Insert([]dns.RR{rr})
+
+	if err := d.exchange(m); err != nil {
+		return err
+	}
+	logger.Infof("Updated %s record via RFC2136: %s -> %s", rec.Type, rec.Name, rec.Content)
+	return nil
+}
+
+// DeleteRecord removes every record matching rec's type and name from the zone.
+func (d *RFC2136DNSManager) DeleteRecord(ctx context.Context, rec Record) error {
+	m := new(dns.Msg)
+	m.SetUpdate(d.zoneName)
+	m.RemoveRRset([]dns.RR{&dns.ANY{Hdr: dns.RR_Header{Name: dns.Fqdn(rec.Name), Rrtype: dns.StringToType[rec.Type], Class: dns.ClassANY}}})
+
+	if err := d.exchange(m); err != nil {
+		return err
+	}
+	logger.Infof("Deleted %s record via RFC2136: %s", rec.Type, rec.Name)
+	return nil
+}
+
+// ListRecords is not implemented: RFC 2136 has no standard zone-transfer-free
+// way to enumerate records, and AXFR support varies too much by server to
+// assume it's available here.
+func (d *RFC2136DNSManager) ListRecords(ctx context.Context) ([]Record, error) {
+	return nil, fmt.Errorf("rfc2136: listing records is not supported")
+}
+
+func ttlOrDefault(ttl int) int {
+	if ttl < 1 {
+		return 60
+	}
+	return ttl
+}
+
+var _ DNSProvider = (*RFC2136DNSManager)(nil)
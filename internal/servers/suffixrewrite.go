@@ -0,0 +1,72 @@
+package servers
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// SuffixRewriteRule rewrites a record name before it's checked against the
+// managed zone and sent to Cloudflare. Rules let operators express
+// site-specific renames (e.g. a legacy short hostname that should resolve
+// under a different suffix) via configuration instead of patching the
+// binary.
+type SuffixRewriteRule struct {
+	from  string
+	to    string
+	regex *regexp.Regexp // non-nil for a "regex:" rule; nil for a plain suffix rule
+}
+
+// Apply rewrites name if the rule matches it, reporting whether a rewrite
+// happened.
+func (rule SuffixRewriteRule) Apply(name string) (string, bool) {
+	if rule.regex != nil {
+		if !rule.regex.MatchString(name) {
+			return name, false
+		}
+		return rule.regex.ReplaceAllString(name, rule.to), true
+	}
+
+	if !strings.HasSuffix(name, rule.from) {
+		return name, false
+	}
+	return strings.TrimSuffix(name, rule.from) + rule.to, true
+}
+
+// ParseSuffixRewriteRule parses a single "--dns-rewrite" rule in the form
+// "from=>to". If from starts with "regex:", the remainder is compiled as a
+// regular expression and "to" is used as its replacement (capture groups
+// are supported); otherwise a name ending in the plain suffix from has that
+// suffix replaced by to, so "ls90=>ls90.co" gives the same result as the old
+// hardcoded "ls90" -> "ls90.co" rewrite.
+func ParseSuffixRewriteRule(spec string) (SuffixRewriteRule, error) {
+	parts := strings.SplitN(spec, "=>", 2)
+	if len(parts) != 2 {
+		return SuffixRewriteRule{}, fmt.Errorf("invalid suffix rewrite rule %q: expected form from=>to", spec)
+	}
+	from, to := parts[0], parts[1]
+
+	if rest, ok := strings.CutPrefix(from, "regex:"); ok {
+		re, err := regexp.Compile(rest)
+		if err != nil {
+			return SuffixRewriteRule{}, fmt.Errorf("invalid regex in suffix rewrite rule %q: %w", spec, err)
+		}
+		return SuffixRewriteRule{from: from, to: to, regex: re}, nil
+	}
+
+	return SuffixRewriteRule{from: from, to: to}, nil
+}
+
+// ParseSuffixRewriteRules parses a "--dns-rewrite" flag's worth of rule
+// specs, in order.
+func ParseSuffixRewriteRules(specs []string) ([]SuffixRewriteRule, error) {
+	rules := make([]SuffixRewriteRule, 0, len(specs))
+	for _, spec := range specs {
+		rule, err := ParseSuffixRewriteRule(spec)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
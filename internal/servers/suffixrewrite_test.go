@@ -0,0 +1,65 @@
+package servers
+
+import "testing"
+
+func TestParseSuffixRewriteRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		apply   string
+		want    string
+		wantOK  bool
+		wantErr bool
+	}{
+		{name: "plain suffix match", spec: "ls90=>ls90.co", apply: "host.ls90", want: "host.ls90.co", wantOK: true},
+		{name: "plain suffix no match", spec: "ls90=>ls90.co", apply: "host.example.com", want: "host.example.com", wantOK: false},
+		{name: "regex rule", spec: "regex:^(.+)\\.old\\.example\\.com$=>$1.new.example.com", apply: "app.old.example.com", want: "app.new.example.com", wantOK: true},
+		{name: "regex rule no match", spec: "regex:^(.+)\\.old\\.example\\.com$=>$1.new.example.com", apply: "app.example.com", want: "app.example.com", wantOK: false},
+		{name: "missing separator", spec: "ls90-ls90.co", wantErr: true},
+		{name: "invalid regex", spec: "regex:(=>broken", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseSuffixRewriteRule(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseSuffixRewriteRule(%q) expected an error", tt.spec)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseSuffixRewriteRule(%q) unexpected error: %v", tt.spec, err)
+			}
+
+			got, ok := rule.Apply(tt.apply)
+			if ok != tt.wantOK {
+				t.Fatalf("Apply(%q) matched = %v, want %v", tt.apply, ok, tt.wantOK)
+			}
+			if got != tt.want {
+				t.Fatalf("Apply(%q) = %q, want %q", tt.apply, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseSuffixRewriteRulesAppliesFirstMatchInOrder(t *testing.T) {
+	rules, err := ParseSuffixRewriteRules([]string{"ls90=>ls90.co", "ls90.co=>wrong"})
+	if err != nil {
+		t.Fatalf("ParseSuffixRewriteRules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+
+	var rewritten string
+	var matched bool
+	for _, rule := range rules {
+		if rewritten, matched = rule.Apply("host.ls90"); matched {
+			break
+		}
+	}
+	if !matched || rewritten != "host.ls90.co" {
+		t.Fatalf("expected the first matching rule to win, got %q (matched=%v)", rewritten, matched)
+	}
+}
@@ -0,0 +1,10 @@
+package servers
+
+import "strings"
+
+// hostnameInZone reports whether name falls under zone, requiring a label
+// boundary: name must equal zone or end in "."+zone, so a zone
+// "example.com" doesn't also claim "notexample.com" or "myexample.com".
+func hostnameInZone(name, zone string) bool {
+	return name == zone || strings.HasSuffix(name, "."+zone)
+}